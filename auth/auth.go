@@ -0,0 +1,192 @@
+// Package auth provides session-cookie login and CSRF protection for
+// goblog's state-changing endpoints.
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// sessionCookie is the name of the session cookie set on login.
+const sessionCookie = "goblog_session"
+
+// ErrInvalidCredentials is returned by Login when the username or
+// password do not match Config.
+var ErrInvalidCredentials = errors.New("auth: invalid credentials")
+
+// Config holds the single account and session policy guarding the admin
+// endpoints.
+type Config struct {
+	Username     string
+	PasswordHash string
+	SessionTTL   time.Duration
+}
+
+// CheckPassword reports whether username and password match cfg. The
+// bcrypt comparison always runs, even on a username mismatch, so a wrong
+// username and a wrong password take the same amount of time.
+func (cfg Config) CheckPassword(username, password string) bool {
+	match := subtle.ConstantTimeCompare([]byte(username), []byte(cfg.Username)) == 1
+	validPassword := bcrypt.CompareHashAndPassword([]byte(cfg.PasswordHash), []byte(password)) == nil
+
+	return match && validPassword
+}
+
+// session is a single logged-in session.
+type session struct {
+	username string
+	expires  time.Time
+}
+
+// Store is an in-memory session store keyed by opaque session token.
+type Store struct {
+	mu       sync.Mutex
+	sessions map[string]session
+	ttl      time.Duration
+}
+
+// NewStore creates an empty Store whose sessions expire after ttl.
+func NewStore(ttl time.Duration) *Store {
+	return &Store{sessions: make(map[string]session), ttl: ttl}
+}
+
+// Create starts a new session for username and returns its token.
+func (s *Store) Create(username string) (string, error) {
+	token, err := randomToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	s.sessions[token] = session{username: username, expires: time.Now().Add(s.ttl)}
+	s.mu.Unlock()
+
+	return token, nil
+}
+
+// Valid reports whether token names a live, unexpired session.
+func (s *Store) Valid(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[token]
+	if !ok {
+		return false
+	}
+
+	if time.Now().After(sess.expires) {
+		delete(s.sessions, token)
+		return false
+	}
+
+	return true
+}
+
+// Delete ends the session named by token, if any.
+func (s *Store) Delete(token string) {
+	s.mu.Lock()
+	delete(s.sessions, token)
+	s.mu.Unlock()
+}
+
+// randomToken returns a URL-safe, base64-encoded string of n random bytes.
+func randomToken(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Authenticator ties a Config to a session Store and provides the HTTP
+// plumbing for login, logout and route protection.
+type Authenticator struct {
+	Config Config
+	Store  *Store
+}
+
+// New creates an Authenticator backed by a fresh in-memory session store.
+func New(cfg Config) *Authenticator {
+	return &Authenticator{Config: cfg, Store: NewStore(cfg.SessionTTL)}
+}
+
+// Login verifies username/password against a.Config and, on success,
+// starts a session and sets its cookie on w.
+func (a *Authenticator) Login(w http.ResponseWriter, r *http.Request, username, password string) error {
+	if !a.Config.CheckPassword(username, password) {
+		return ErrInvalidCredentials
+	}
+
+	token, err := a.Store.Create(username)
+	if err != nil {
+		return err
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    token,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   isSecure(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return nil
+}
+
+// Logout clears r's session, if any, and expires its cookie on w.
+func (a *Authenticator) Logout(w http.ResponseWriter, r *http.Request) {
+	if c, err := r.Cookie(sessionCookie); err == nil {
+		a.Store.Delete(c.Value)
+	}
+
+	http.SetCookie(w, &http.Cookie{
+		Name:     sessionCookie,
+		Value:    "",
+		Path:     "/",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   isSecure(r),
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+// isSecure reports whether r arrived over TLS, including behind a
+// TLS-terminating reverse proxy that sets X-Forwarded-Proto. Cookies
+// marked Secure are silently dropped by browsers over plain HTTP, so
+// this must match how the request actually reached us, not just assume
+// TLS is always in front.
+func isSecure(r *http.Request) bool {
+	return r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https"
+}
+
+// LoggedIn reports whether r carries a valid session cookie.
+func (a *Authenticator) LoggedIn(r *http.Request) bool {
+	c, err := r.Cookie(sessionCookie)
+	if err != nil {
+		return false
+	}
+
+	return a.Store.Valid(c.Value)
+}
+
+// RequireAuth wraps next so that requests without a valid session are
+// redirected to /login instead of reaching it.
+func (a *Authenticator) RequireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !a.LoggedIn(r) {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
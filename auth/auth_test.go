@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestCheckPassword(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("bcrypt.GenerateFromPassword() error: %v", err)
+	}
+
+	cfg := Config{Username: "admin", PasswordHash: string(hash)}
+
+	if !cfg.CheckPassword("admin", "correct horse") {
+		t.Error("CheckPassword() = false for correct credentials, want true")
+	}
+	if cfg.CheckPassword("admin", "wrong") {
+		t.Error("CheckPassword() = true for wrong password, want false")
+	}
+	if cfg.CheckPassword("someone-else", "correct horse") {
+		t.Error("CheckPassword() = true for wrong username, want false")
+	}
+}
+
+func TestStoreCreateValidDelete(t *testing.T) {
+	s := NewStore(time.Minute)
+
+	token, err := s.Create("admin")
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+
+	if !s.Valid(token) {
+		t.Error("Valid() = false right after Create(), want true")
+	}
+
+	s.Delete(token)
+	if s.Valid(token) {
+		t.Error("Valid() = true after Delete(), want false")
+	}
+}
+
+func TestStoreExpiry(t *testing.T) {
+	s := NewStore(-time.Second)
+
+	token, err := s.Create("admin")
+	if err != nil {
+		t.Fatalf("Create() error: %v", err)
+	}
+
+	if s.Valid(token) {
+		t.Error("Valid() = true for an already-expired session, want false")
+	}
+}
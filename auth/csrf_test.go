@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestCSRFTokenStable(t *testing.T) {
+	r := httptest.NewRequest("GET", "/submit/", nil)
+	w := httptest.NewRecorder()
+
+	first := CSRFToken(w, r)
+	if first == "" {
+		t.Fatal("CSRFToken() returned empty token")
+	}
+
+	resp := w.Result()
+	cookie := resp.Cookies()[0]
+	if cookie.Name != csrfCookieInsecure {
+		t.Fatalf("cookie name = %q, want %q", cookie.Name, csrfCookieInsecure)
+	}
+
+	r2 := httptest.NewRequest("GET", "/submit/", nil)
+	r2.AddCookie(cookie)
+
+	second := CSRFToken(httptest.NewRecorder(), r2)
+	if second != first {
+		t.Errorf("CSRFToken() = %q on second call, want stable %q", second, first)
+	}
+}
+
+func TestCSRFTokenSecureOverTLS(t *testing.T) {
+	r := httptest.NewRequest("GET", "/submit/", nil)
+	r.TLS = &tls.ConnectionState{}
+	w := httptest.NewRecorder()
+
+	if CSRFToken(w, r) == "" {
+		t.Fatal("CSRFToken() returned empty token")
+	}
+
+	cookie := w.Result().Cookies()[0]
+	if cookie.Name != csrfCookieSecure {
+		t.Errorf("cookie name = %q, want %q", cookie.Name, csrfCookieSecure)
+	}
+	if !cookie.Secure {
+		t.Error("cookie Secure = false, want true over TLS")
+	}
+}
+
+func TestRequireCSRF(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	handler := RequireCSRF(ok)
+
+	// Mint a token.
+	mintReq := httptest.NewRequest("GET", "/submit/", nil)
+	mintRec := httptest.NewRecorder()
+	token := CSRFToken(mintRec, mintReq)
+	cookie := mintRec.Result().Cookies()[0]
+
+	form := url.Values{"csrf_token": {token}}
+	req := httptest.NewRequest("POST", "/submit/", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.AddCookie(cookie)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("valid token: status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	badReq := httptest.NewRequest("POST", "/submit/", strings.NewReader(url.Values{"csrf_token": {"wrong"}}.Encode()))
+	badReq.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	badReq.AddCookie(cookie)
+
+	badRec := httptest.NewRecorder()
+	handler.ServeHTTP(badRec, badReq)
+	if badRec.Code != http.StatusForbidden {
+		t.Errorf("mismatched token: status = %d, want %d", badRec.Code, http.StatusForbidden)
+	}
+}
@@ -0,0 +1,178 @@
+// Package blog implements the in-memory blog state shared by goblog's
+// front-end handlers: loading entries from a Storage backend and keeping
+// them in a sorted order for rendering.
+package blog
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// BlogEntry is a single blog post.
+type BlogEntry struct {
+	ID      int
+	Title   string
+	Content string
+	Date    time.Time
+	Draft   bool
+	Tags    []string
+}
+
+// Blog is the in-memory facade handlers render from. It keeps a sorted
+// copy of every entry in Storage, refreshed after each write, plus a tag
+// index used by the "/tag/{name}" listing. Both are guarded by mu, since
+// net/http serves handlers concurrently and some of them write.
+type Blog struct {
+	Storage Storage
+
+	mu       sync.RWMutex
+	entries  []*BlogEntry
+	tagIndex map[string][]*BlogEntry
+}
+
+// ByDate sorts a slice of entries newest-first.
+type ByDate struct {
+	Entries []*BlogEntry
+}
+
+func (b ByDate) Len() int      { return len(b.Entries) }
+func (b ByDate) Swap(i, j int) { b.Entries[i], b.Entries[j] = b.Entries[j], b.Entries[i] }
+func (b ByDate) Less(i, j int) bool {
+	return b.Entries[i].Date.After(b.Entries[j].Date)
+}
+
+// New creates a Blog backed by storage, loading its current entries.
+func New(storage Storage) (*Blog, error) {
+	b := &Blog{Storage: storage}
+	if err := b.Refresh(); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// Entries returns a snapshot of the current entries, newest first. The
+// returned slice is a copy, safe to range over even if a concurrent
+// AddEntry or Refresh is in flight.
+func (b *Blog) Entries() []*BlogEntry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	out := make([]*BlogEntry, len(b.entries))
+	copy(out, b.entries)
+
+	return out
+}
+
+// Refresh reloads Entries from Storage, sorted newest-first, and rebuilds
+// the tag index from scratch. Called at startup and after any write that
+// doesn't go through AddEntry (edit, delete, import).
+func (b *Blog) Refresh() error {
+	entries, err := b.Storage.List(0, -1)
+	if err != nil {
+		return err
+	}
+
+	sort.Sort(ByDate{Entries: entries})
+
+	b.mu.Lock()
+	b.entries = entries
+	b.rebuildTagIndex()
+	b.mu.Unlock()
+
+	return nil
+}
+
+// AddEntry persists entry through Storage, which assigns it an ID, then
+// inserts it into Entries and the tag index in place, without re-scanning
+// Storage.
+func (b *Blog) AddEntry(entry *BlogEntry) error {
+	if err := b.Storage.Add(entry); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.insertSorted(entry)
+	b.indexTags(entry)
+	b.mu.Unlock()
+
+	return nil
+}
+
+// insertSorted inserts entry into entries, keeping newest-first order.
+// Callers must hold mu.
+func (b *Blog) insertSorted(entry *BlogEntry) {
+	i := sort.Search(len(b.entries), func(i int) bool {
+		return b.entries[i].Date.Before(entry.Date)
+	})
+
+	b.entries = append(b.entries, nil)
+	copy(b.entries[i+1:], b.entries[i:])
+	b.entries[i] = entry
+}
+
+// indexTags adds entry to the tag index under each of its Tags, keeping
+// each tag's list newest-first. Callers must hold mu.
+func (b *Blog) indexTags(entry *BlogEntry) {
+	if b.tagIndex == nil {
+		b.tagIndex = make(map[string][]*BlogEntry)
+	}
+
+	for _, tag := range entry.Tags {
+		list := b.tagIndex[tag]
+		i := sort.Search(len(list), func(i int) bool {
+			return list[i].Date.Before(entry.Date)
+		})
+
+		list = append(list, nil)
+		copy(list[i+1:], list[i:])
+		list[i] = entry
+		b.tagIndex[tag] = list
+	}
+}
+
+// rebuildTagIndex recomputes the tag index from the current entries.
+// Callers must hold mu.
+func (b *Blog) rebuildTagIndex() {
+	idx := make(map[string][]*BlogEntry)
+
+	for _, entry := range b.entries {
+		for _, tag := range entry.Tags {
+			idx[tag] = append(idx[tag], entry)
+		}
+	}
+
+	b.tagIndex = idx
+}
+
+// Tagged returns the entries tagged with name, newest first.
+func (b *Blog) Tagged(name string) []*BlogEntry {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	tagged := b.tagIndex[name]
+	out := make([]*BlogEntry, len(tagged))
+	copy(out, tagged)
+
+	return out
+}
+
+// TagNames returns the known tag names, sorted alphabetically.
+func (b *Blog) TagNames() []string {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	names := make([]string, 0, len(b.tagIndex))
+	for name := range b.tagIndex {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Close releases the underlying Storage.
+func (b *Blog) Close() error {
+	return b.Storage.Close()
+}
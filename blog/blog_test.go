@@ -0,0 +1,78 @@
+package blog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestBlog(t *testing.T) *Blog {
+	t.Helper()
+
+	s, err := NewJSONStorage(filepath.Join(t.TempDir(), "entries.json"))
+	if err != nil {
+		t.Fatalf("NewJSONStorage() error: %v", err)
+	}
+
+	b, err := New(s)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+
+	return b
+}
+
+func TestBlogAddEntryIndexesTags(t *testing.T) {
+	b := newTestBlog(t)
+
+	first := &BlogEntry{Title: "First", Date: time.Now().Add(-time.Hour), Tags: []string{"go", "web"}}
+	if err := b.AddEntry(first); err != nil {
+		t.Fatalf("AddEntry() error: %v", err)
+	}
+
+	second := &BlogEntry{Title: "Second", Date: time.Now(), Tags: []string{"go"}}
+	if err := b.AddEntry(second); err != nil {
+		t.Fatalf("AddEntry() error: %v", err)
+	}
+
+	entries := b.Entries()
+	if len(entries) != 2 || entries[0].Title != "Second" {
+		t.Fatalf("Entries() = %+v, want [Second, First]", entries)
+	}
+
+	go_ := b.Tagged("go")
+	if len(go_) != 2 {
+		t.Fatalf("Tagged(%q) = %+v, want 2 entries", "go", go_)
+	}
+
+	web := b.Tagged("web")
+	if len(web) != 1 || web[0].Title != "First" {
+		t.Fatalf("Tagged(%q) = %+v, want [First]", "web", web)
+	}
+
+	names := b.TagNames()
+	if len(names) != 2 || names[0] != "go" || names[1] != "web" {
+		t.Fatalf("TagNames() = %v, want [go web]", names)
+	}
+}
+
+func TestBlogRefreshRebuildsTagIndex(t *testing.T) {
+	b := newTestBlog(t)
+
+	entry := &BlogEntry{Title: "Only", Date: time.Now(), Tags: []string{"misc"}}
+	if err := b.AddEntry(entry); err != nil {
+		t.Fatalf("AddEntry() error: %v", err)
+	}
+
+	entry.Tags = nil
+	if err := b.Storage.Update(entry); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+	if err := b.Refresh(); err != nil {
+		t.Fatalf("Refresh() error: %v", err)
+	}
+
+	if tagged := b.Tagged("misc"); len(tagged) != 0 {
+		t.Errorf("Tagged(%q) after Refresh = %+v, want none", "misc", tagged)
+	}
+}
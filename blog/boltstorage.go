@@ -0,0 +1,216 @@
+package blog
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	entriesBucket = []byte("entries")
+	byDateBucket  = []byte("by_date")
+)
+
+// BoltStorage is a Storage backed by a BoltDB file. Entries are stored as
+// JSON in entriesBucket, keyed by an 8-byte big-endian ID; byDateBucket
+// indexes entries by (date, ID) so List can page newest-first without a
+// full bucket scan.
+type BoltStorage struct {
+	db *bolt.DB
+}
+
+// NewBoltStorage opens (creating if necessary) a BoltDB file at path.
+func NewBoltStorage(path string) (*BoltStorage, error) {
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		if _, err := tx.CreateBucketIfNotExists(entriesBucket); err != nil {
+			return err
+		}
+		_, err := tx.CreateBucketIfNotExists(byDateBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &BoltStorage{db: db}, nil
+}
+
+func idKey(id int) []byte {
+	k := make([]byte, 8)
+	binary.BigEndian.PutUint64(k, uint64(id))
+	return k
+}
+
+func dateKey(entry *BlogEntry) []byte {
+	k := make([]byte, 16)
+	binary.BigEndian.PutUint64(k, uint64(entry.Date.UnixNano()))
+	binary.BigEndian.PutUint64(k[8:], uint64(entry.ID))
+	return k
+}
+
+func (s *BoltStorage) Get(id int) (*BlogEntry, error) {
+	var entry *BlogEntry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(entriesBucket).Get(idKey(id))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		entry = &BlogEntry{}
+		return json.Unmarshal(data, entry)
+	})
+
+	return entry, err
+}
+
+func (s *BoltStorage) List(offset, limit int) ([]*BlogEntry, error) {
+	var out []*BlogEntry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		eb := tx.Bucket(entriesBucket)
+		c := tx.Bucket(byDateBucket).Cursor()
+
+		i := 0
+		for k, v := c.Last(); k != nil; k, v = c.Prev() {
+			if i < offset {
+				i++
+				continue
+			}
+			if limit >= 0 && len(out) >= limit {
+				break
+			}
+
+			data := eb.Get(v)
+			if data == nil {
+				i++
+				continue
+			}
+
+			entry := &BlogEntry{}
+			if err := json.Unmarshal(data, entry); err != nil {
+				return err
+			}
+
+			out = append(out, entry)
+			i++
+		}
+
+		return nil
+	})
+
+	return out, err
+}
+
+func (s *BoltStorage) Add(entry *BlogEntry) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		eb := tx.Bucket(entriesBucket)
+
+		id, err := eb.NextSequence()
+		if err != nil {
+			return err
+		}
+		entry.ID = int(id)
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+
+		if err := eb.Put(idKey(entry.ID), data); err != nil {
+			return err
+		}
+
+		return tx.Bucket(byDateBucket).Put(dateKey(entry), idKey(entry.ID))
+	})
+}
+
+func (s *BoltStorage) Update(entry *BlogEntry) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		eb := tx.Bucket(entriesBucket)
+
+		existing := eb.Get(idKey(entry.ID))
+		if existing == nil {
+			return ErrNotFound
+		}
+
+		old := &BlogEntry{}
+		if err := json.Unmarshal(existing, old); err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		if err := eb.Put(idKey(entry.ID), data); err != nil {
+			return err
+		}
+
+		db := tx.Bucket(byDateBucket)
+		if err := db.Delete(dateKey(old)); err != nil {
+			return err
+		}
+
+		return db.Put(dateKey(entry), idKey(entry.ID))
+	})
+}
+
+func (s *BoltStorage) Delete(id int) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		eb := tx.Bucket(entriesBucket)
+
+		data := eb.Get(idKey(id))
+		if data == nil {
+			return ErrNotFound
+		}
+
+		entry := &BlogEntry{}
+		if err := json.Unmarshal(data, entry); err != nil {
+			return err
+		}
+
+		if err := eb.Delete(idKey(id)); err != nil {
+			return err
+		}
+
+		return tx.Bucket(byDateBucket).Delete(dateKey(entry))
+	})
+}
+
+func (s *BoltStorage) Search(query string) ([]*BlogEntry, error) {
+	q := strings.ToLower(query)
+	var out []*BlogEntry
+
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(entriesBucket).ForEach(func(k, v []byte) error {
+			entry := &BlogEntry{}
+			if err := json.Unmarshal(v, entry); err != nil {
+				return err
+			}
+
+			if strings.Contains(strings.ToLower(entry.Title), q) || strings.Contains(strings.ToLower(entry.Content), q) {
+				out = append(out, entry)
+			}
+
+			return nil
+		})
+	})
+
+	sort.Sort(ByDate{Entries: out})
+
+	return out, err
+}
+
+func (s *BoltStorage) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,83 @@
+package blog
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/adrg/frontmatter"
+)
+
+// importMatter is the TOML/YAML frontmatter expected on each imported
+// Markdown file.
+type importMatter struct {
+	Title string    `yaml:"title" toml:"title"`
+	Date  time.Time `yaml:"date" toml:"date"`
+	Draft bool      `yaml:"draft" toml:"draft"`
+	Tags  []string  `yaml:"tags" toml:"tags"`
+}
+
+// Import scans dir for "*.md" files, parses TOML/YAML frontmatter from
+// each and merges the result into storage: a file whose title matches an
+// existing entry updates it, otherwise a new entry is added. It returns
+// the number of files imported.
+func Import(storage Storage, dir string) (int, error) {
+	existing, err := storage.List(0, -1)
+	if err != nil {
+		return 0, err
+	}
+
+	byTitle := make(map[string]*BlogEntry, len(existing))
+	for _, e := range existing {
+		byTitle[e.Title] = e
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.md"))
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return count, err
+		}
+
+		var matter importMatter
+		rest, err := frontmatter.Parse(f, &matter)
+		f.Close()
+		if err != nil {
+			return count, err
+		}
+
+		entry := &BlogEntry{
+			Title:   matter.Title,
+			Content: string(rest),
+			Date:    matter.Date,
+			Draft:   matter.Draft,
+			Tags:    matter.Tags,
+		}
+		if entry.Title == "" {
+			entry.Title = strings.TrimSuffix(filepath.Base(path), ".md")
+		}
+		if entry.Date.IsZero() {
+			entry.Date = time.Now()
+		}
+
+		if old, ok := byTitle[entry.Title]; ok {
+			entry.ID = old.ID
+			err = storage.Update(entry)
+		} else {
+			err = storage.Add(entry)
+		}
+		if err != nil {
+			return count, err
+		}
+
+		count++
+	}
+
+	return count, nil
+}
@@ -0,0 +1,172 @@
+package blog
+
+import (
+	"encoding/json"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// JSONStorage is a Storage backed by a single JSON file, rewritten in full
+// on every write. Kept for compatibility with existing installs;
+// BoltStorage or SQLiteStorage should be preferred for anything beyond a
+// handful of posts, since a crash between a write and the next save can
+// lose data.
+type JSONStorage struct {
+	mu      sync.Mutex
+	path    string
+	entries []*BlogEntry
+	nextID  int
+}
+
+type jsonDoc struct {
+	Entries []*BlogEntry
+}
+
+// NewJSONStorage loads entries from path, creating an empty store if the
+// file does not exist.
+func NewJSONStorage(path string) (*JSONStorage, error) {
+	s := &JSONStorage{path: path, nextID: 1}
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return s, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var doc jsonDoc
+	if err := json.NewDecoder(f).Decode(&doc); err != nil {
+		return nil, err
+	}
+
+	s.entries = doc.Entries
+	for _, e := range s.entries {
+		if e.ID >= s.nextID {
+			s.nextID = e.ID + 1
+		}
+	}
+
+	return s, nil
+}
+
+func (s *JSONStorage) Get(id int) (*BlogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		if e.ID == id {
+			return e, nil
+		}
+	}
+
+	return nil, ErrNotFound
+}
+
+func (s *JSONStorage) List(offset, limit int) ([]*BlogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sorted := make([]*BlogEntry, len(s.entries))
+	copy(sorted, s.entries)
+	sort.Sort(ByDate{Entries: sorted})
+
+	if offset >= len(sorted) {
+		return nil, nil
+	}
+
+	end := len(sorted)
+	if limit >= 0 && offset+limit < end {
+		end = offset + limit
+	}
+
+	out := make([]*BlogEntry, end-offset)
+	copy(out, sorted[offset:end])
+
+	return out, nil
+}
+
+func (s *JSONStorage) Add(entry *BlogEntry) error {
+	s.mu.Lock()
+	entry.ID = s.nextID
+	s.nextID++
+	s.entries = append(s.entries, entry)
+	s.mu.Unlock()
+
+	return s.flush()
+}
+
+func (s *JSONStorage) Update(entry *BlogEntry) error {
+	s.mu.Lock()
+	found := false
+	for i, e := range s.entries {
+		if e.ID == entry.ID {
+			s.entries[i] = entry
+			found = true
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if !found {
+		return ErrNotFound
+	}
+
+	return s.flush()
+}
+
+func (s *JSONStorage) Delete(id int) error {
+	s.mu.Lock()
+	idx := -1
+	for i, e := range s.entries {
+		if e.ID == id {
+			idx = i
+			break
+		}
+	}
+	if idx >= 0 {
+		s.entries = append(s.entries[:idx], s.entries[idx+1:]...)
+	}
+	s.mu.Unlock()
+
+	if idx < 0 {
+		return ErrNotFound
+	}
+
+	return s.flush()
+}
+
+func (s *JSONStorage) Search(query string) ([]*BlogEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := strings.ToLower(query)
+	var out []*BlogEntry
+	for _, e := range s.entries {
+		if strings.Contains(strings.ToLower(e.Title), q) || strings.Contains(strings.ToLower(e.Content), q) {
+			out = append(out, e)
+		}
+	}
+
+	sort.Sort(ByDate{Entries: out})
+
+	return out, nil
+}
+
+func (s *JSONStorage) Close() error { return nil }
+
+// flush serializes the full entry set to disk.
+func (s *JSONStorage) flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, err := os.Create(s.path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return json.NewEncoder(f).Encode(jsonDoc{Entries: s.entries})
+}
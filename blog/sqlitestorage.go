@@ -0,0 +1,252 @@
+package blog
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStorage is a Storage backed by SQLite, with an FTS5 virtual table
+// mirroring title and content for Search.
+type SQLiteStorage struct {
+	db *sql.DB
+}
+
+var sqliteSchema = []string{
+	`CREATE TABLE IF NOT EXISTS entries (
+		id      INTEGER PRIMARY KEY AUTOINCREMENT,
+		title   TEXT NOT NULL,
+		content TEXT NOT NULL,
+		date    DATETIME NOT NULL
+	)`,
+	`CREATE VIRTUAL TABLE IF NOT EXISTS entries_fts USING fts5(
+		title, content, content='entries', content_rowid='id'
+	)`,
+	`CREATE TRIGGER IF NOT EXISTS entries_ai AFTER INSERT ON entries BEGIN
+		INSERT INTO entries_fts(rowid, title, content) VALUES (new.id, new.title, new.content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS entries_ad AFTER DELETE ON entries BEGIN
+		INSERT INTO entries_fts(entries_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content);
+	END`,
+	`CREATE TRIGGER IF NOT EXISTS entries_au AFTER UPDATE ON entries BEGIN
+		INSERT INTO entries_fts(entries_fts, rowid, title, content) VALUES ('delete', old.id, old.title, old.content);
+		INSERT INTO entries_fts(rowid, title, content) VALUES (new.id, new.title, new.content);
+	END`,
+}
+
+// sqliteColumnMigrations are columns added to entries after the table
+// first shipped. CREATE TABLE IF NOT EXISTS above is a no-op against a
+// database file created under an earlier schema, so each one is applied
+// with ALTER TABLE, guarded by a check that it isn't already there.
+var sqliteColumnMigrations = []struct {
+	column string
+	ddl    string
+}{
+	{"draft", `ALTER TABLE entries ADD COLUMN draft BOOLEAN NOT NULL DEFAULT 0`},
+	{"tags", `ALTER TABLE entries ADD COLUMN tags TEXT NOT NULL DEFAULT ''`},
+}
+
+// NewSQLiteStorage opens (creating and migrating if necessary) a SQLite
+// database at path.
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range sqliteSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			db.Close()
+			return nil, fmt.Errorf("blog: sqlite schema: %w", err)
+		}
+	}
+
+	if err := migrateColumns(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteStorage{db: db}, nil
+}
+
+// migrateColumns adds any column in sqliteColumnMigrations missing from
+// entries, e.g. an entries.json-era SQLite file created before draft or
+// tags existed.
+func migrateColumns(db *sql.DB) error {
+	existing, err := entriesColumns(db)
+	if err != nil {
+		return err
+	}
+
+	for _, m := range sqliteColumnMigrations {
+		if existing[m.column] {
+			continue
+		}
+		if _, err := db.Exec(m.ddl); err != nil {
+			return fmt.Errorf("blog: sqlite migrate column %q: %w", m.column, err)
+		}
+	}
+
+	return nil
+}
+
+// entriesColumns returns the set of column names currently on the entries
+// table.
+func entriesColumns(db *sql.DB) (map[string]bool, error) {
+	rows, err := db.Query(`PRAGMA table_info(entries)`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols := make(map[string]bool)
+	for rows.Next() {
+		var (
+			cid       int
+			name      string
+			typ       string
+			notNull   int
+			dfltValue sql.NullString
+			pk        int
+		)
+		if err := rows.Scan(&cid, &name, &typ, &notNull, &dfltValue, &pk); err != nil {
+			return nil, err
+		}
+		cols[name] = true
+	}
+
+	return cols, rows.Err()
+}
+
+func (s *SQLiteStorage) Get(id int) (*BlogEntry, error) {
+	entry := &BlogEntry{}
+	var tags string
+
+	row := s.db.QueryRow(`SELECT id, title, content, date, draft, tags FROM entries WHERE id = ?`, id)
+	if err := row.Scan(&entry.ID, &entry.Title, &entry.Content, &entry.Date, &entry.Draft, &tags); err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	entry.Tags = splitTags(tags)
+
+	return entry, nil
+}
+
+func (s *SQLiteStorage) List(offset, limit int) ([]*BlogEntry, error) {
+	if limit < 0 {
+		limit = -1
+	}
+
+	rows, err := s.db.Query(
+		`SELECT id, title, content, date, draft, tags FROM entries ORDER BY date DESC LIMIT ? OFFSET ?`,
+		limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEntries(rows)
+}
+
+func (s *SQLiteStorage) Add(entry *BlogEntry) error {
+	res, err := s.db.Exec(
+		`INSERT INTO entries (title, content, date, draft, tags) VALUES (?, ?, ?, ?, ?)`,
+		entry.Title, entry.Content, entry.Date, entry.Draft, joinTags(entry.Tags))
+	if err != nil {
+		return err
+	}
+
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	entry.ID = int(id)
+
+	return nil
+}
+
+func (s *SQLiteStorage) Update(entry *BlogEntry) error {
+	res, err := s.db.Exec(
+		`UPDATE entries SET title = ?, content = ?, date = ?, draft = ?, tags = ? WHERE id = ?`,
+		entry.Title, entry.Content, entry.Date, entry.Draft, joinTags(entry.Tags), entry.ID)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) Delete(id int) error {
+	res, err := s.db.Exec(`DELETE FROM entries WHERE id = ?`, id)
+	if err != nil {
+		return err
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *SQLiteStorage) Search(query string) ([]*BlogEntry, error) {
+	rows, err := s.db.Query(
+		`SELECT e.id, e.title, e.content, e.date, e.draft, e.tags FROM entries_fts
+		 JOIN entries e ON e.id = entries_fts.rowid
+		 WHERE entries_fts MATCH ? ORDER BY e.date DESC`, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanEntries(rows)
+}
+
+func (s *SQLiteStorage) Close() error {
+	return s.db.Close()
+}
+
+func scanEntries(rows *sql.Rows) ([]*BlogEntry, error) {
+	var out []*BlogEntry
+
+	for rows.Next() {
+		entry := &BlogEntry{}
+		var tags string
+		if err := rows.Scan(&entry.ID, &entry.Title, &entry.Content, &entry.Date, &entry.Draft, &tags); err != nil {
+			return nil, err
+		}
+		entry.Tags = splitTags(tags)
+		out = append(out, entry)
+	}
+
+	return out, rows.Err()
+}
+
+// joinTags and splitTags store BlogEntry.Tags as a single comma-separated
+// column rather than a join table, consistent with the rest of the schema
+// favoring a single flat entries table.
+func joinTags(tags []string) string {
+	return strings.Join(tags, ",")
+}
+
+func splitTags(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
@@ -0,0 +1,51 @@
+package blog
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// TestSQLiteStorageMigratesOlderSchema simulates opening a database file
+// created before the draft and tags columns existed: CREATE TABLE IF NOT
+// EXISTS is a no-op against it, so NewSQLiteStorage must add the missing
+// columns itself.
+func TestSQLiteStorageMigratesOlderSchema(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "old.sqlite")
+
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("sql.Open() error: %v", err)
+	}
+	for _, stmt := range sqliteSchema {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("create legacy schema: %v", err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO entries (title, content, date) VALUES ('old post', 'body', '2024-01-01')`); err != nil {
+		t.Fatalf("seed legacy row: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("Close() error: %v", err)
+	}
+
+	s, err := NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("NewSQLiteStorage() on legacy schema error: %v", err)
+	}
+	defer s.Close()
+
+	entries, err := s.List(0, -1)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Title != "old post" {
+		t.Fatalf("List() = %+v, want the pre-existing row", entries)
+	}
+
+	entries[0].Draft = true
+	entries[0].Tags = []string{"legacy"}
+	if err := s.Update(entries[0]); err != nil {
+		t.Fatalf("Update() on migrated row error: %v", err)
+	}
+}
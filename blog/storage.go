@@ -0,0 +1,37 @@
+package blog
+
+import "errors"
+
+// ErrNotFound is returned by Get, Update and Delete when no entry with the
+// given ID exists.
+var ErrNotFound = errors.New("blog: entry not found")
+
+// Storage is the persistence backend for blog entries. goblog ships three
+// implementations: JSONStorage (the original flat file, kept for
+// compatibility), BoltStorage and SQLiteStorage.
+type Storage interface {
+	// Get returns the entry with the given ID, or ErrNotFound.
+	Get(id int) (*BlogEntry, error)
+
+	// List returns up to limit entries starting at offset, newest first.
+	// A negative limit means no limit.
+	List(offset, limit int) ([]*BlogEntry, error)
+
+	// Add assigns entry a fresh ID and persists it.
+	Add(entry *BlogEntry) error
+
+	// Update persists changes to an existing entry, or returns
+	// ErrNotFound.
+	Update(entry *BlogEntry) error
+
+	// Delete removes the entry with the given ID, or returns
+	// ErrNotFound.
+	Delete(id int) error
+
+	// Search returns entries whose title or content match query,
+	// newest first.
+	Search(query string) ([]*BlogEntry, error)
+
+	// Close releases any resources held by the backend.
+	Close() error
+}
@@ -0,0 +1,206 @@
+package blog
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// storageOpener opens a fresh Storage backend at path, so the same
+// assertions below can run against every implementation.
+type storageOpener func(path string) (Storage, error)
+
+var storageOpeners = map[string]storageOpener{
+	"bolt":   func(path string) (Storage, error) { return NewBoltStorage(path) },
+	"sqlite": func(path string) (Storage, error) { return NewSQLiteStorage(path) },
+	"json":   func(path string) (Storage, error) { return NewJSONStorage(path) },
+}
+
+func openStorage(t *testing.T, name string, open storageOpener) Storage {
+	t.Helper()
+
+	s, err := open(filepath.Join(t.TempDir(), "entries."+name))
+	if err != nil {
+		t.Fatalf("%s: open error: %v", name, err)
+	}
+	t.Cleanup(func() {
+		if err := s.Close(); err != nil {
+			t.Errorf("%s: Close() error: %v", name, err)
+		}
+	})
+
+	return s
+}
+
+func TestStorageConformanceAddGet(t *testing.T) {
+	for name, open := range storageOpeners {
+		t.Run(name, func(t *testing.T) {
+			s := openStorage(t, name, open)
+
+			entry := &BlogEntry{Title: "Hello", Content: "World", Date: time.Now(), Tags: []string{"a", "b"}}
+			if err := s.Add(entry); err != nil {
+				t.Fatalf("Add() error: %v", err)
+			}
+			if entry.ID == 0 {
+				t.Fatalf("Add() did not assign an ID")
+			}
+
+			got, err := s.Get(entry.ID)
+			if err != nil {
+				t.Fatalf("Get() error: %v", err)
+			}
+			if got.Title != "Hello" {
+				t.Errorf("Get().Title = %q, want %q", got.Title, "Hello")
+			}
+			if len(got.Tags) != 2 || got.Tags[0] != "a" || got.Tags[1] != "b" {
+				t.Errorf("Get().Tags = %v, want [a b]", got.Tags)
+			}
+
+			if _, err := s.Get(entry.ID + 1); err != ErrNotFound {
+				t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+func TestStorageConformanceListOrdering(t *testing.T) {
+	for name, open := range storageOpeners {
+		t.Run(name, func(t *testing.T) {
+			s := openStorage(t, name, open)
+
+			older := &BlogEntry{Title: "older", Date: time.Now().Add(-time.Hour)}
+			newer := &BlogEntry{Title: "newer", Date: time.Now()}
+
+			if err := s.Add(older); err != nil {
+				t.Fatalf("Add() error: %v", err)
+			}
+			if err := s.Add(newer); err != nil {
+				t.Fatalf("Add() error: %v", err)
+			}
+
+			entries, err := s.List(0, -1)
+			if err != nil {
+				t.Fatalf("List() error: %v", err)
+			}
+			if len(entries) != 2 || entries[0].Title != "newer" {
+				t.Fatalf("List() = %+v, want newer first", entries)
+			}
+
+			page, err := s.List(1, 1)
+			if err != nil {
+				t.Fatalf("List(1, 1) error: %v", err)
+			}
+			if len(page) != 1 || page[0].Title != "older" {
+				t.Fatalf("List(1, 1) = %+v, want [older]", page)
+			}
+		})
+	}
+}
+
+func TestStorageConformanceUpdateDeleteSearch(t *testing.T) {
+	for name, open := range storageOpeners {
+		t.Run(name, func(t *testing.T) {
+			s := openStorage(t, name, open)
+
+			entry := &BlogEntry{Title: "Original", Content: "first draft", Date: time.Now(), Draft: true}
+			if err := s.Add(entry); err != nil {
+				t.Fatalf("Add() error: %v", err)
+			}
+
+			entry.Title = "Updated"
+			entry.Draft = false
+			entry.Tags = []string{"go"}
+			if err := s.Update(entry); err != nil {
+				t.Fatalf("Update() error: %v", err)
+			}
+
+			got, err := s.Get(entry.ID)
+			if err != nil {
+				t.Fatalf("Get() error: %v", err)
+			}
+			if got.Title != "Updated" {
+				t.Errorf("Get().Title = %q, want %q", got.Title, "Updated")
+			}
+			if got.Draft {
+				t.Errorf("Get().Draft = true, want false after Update")
+			}
+			if len(got.Tags) != 1 || got.Tags[0] != "go" {
+				t.Errorf("Get().Tags = %v, want [go]", got.Tags)
+			}
+
+			results, err := s.Search("updated")
+			if err != nil {
+				t.Fatalf("Search() error: %v", err)
+			}
+			if len(results) != 1 {
+				t.Fatalf("Search() = %+v, want 1 result", results)
+			}
+
+			if err := s.Delete(entry.ID); err != nil {
+				t.Fatalf("Delete() error: %v", err)
+			}
+			if err := s.Delete(entry.ID); err != ErrNotFound {
+				t.Errorf("Delete(already deleted) error = %v, want ErrNotFound", err)
+			}
+		})
+	}
+}
+
+// TestBoltStorageDateIndexReordersOnUpdate guards the fiddly part of
+// BoltStorage: Update must delete the stale byDateBucket entry and insert
+// a new one, not just overwrite entriesBucket, or List order rots as
+// entries are edited.
+func TestBoltStorageDateIndexReordersOnUpdate(t *testing.T) {
+	s := openStorage(t, "bolt", storageOpeners["bolt"])
+
+	a := &BlogEntry{Title: "a", Date: time.Now().Add(-2 * time.Hour)}
+	b := &BlogEntry{Title: "b", Date: time.Now().Add(-time.Hour)}
+	if err := s.Add(a); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+	if err := s.Add(b); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	b.Date = time.Now().Add(-3 * time.Hour)
+	if err := s.Update(b); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	entries, err := s.List(0, -1)
+	if err != nil {
+		t.Fatalf("List() error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Title != "a" {
+		t.Fatalf("List() = %+v, want [a, b] after re-dating b earlier", entries)
+	}
+}
+
+// TestSQLiteStorageSearchReflectsUpdate guards the FTS5 external-content
+// triggers: a Search for the old content must miss and a Search for the
+// new content must hit after Update, not just after Add.
+func TestSQLiteStorageSearchReflectsUpdate(t *testing.T) {
+	s := openStorage(t, "sqlite", storageOpeners["sqlite"])
+
+	entry := &BlogEntry{Title: "Launch", Content: "alpha content", Date: time.Now()}
+	if err := s.Add(entry); err != nil {
+		t.Fatalf("Add() error: %v", err)
+	}
+
+	entry.Content = "beta content"
+	if err := s.Update(entry); err != nil {
+		t.Fatalf("Update() error: %v", err)
+	}
+
+	if results, err := s.Search("alpha"); err != nil {
+		t.Fatalf("Search() error: %v", err)
+	} else if len(results) != 0 {
+		t.Errorf("Search(%q) = %+v, want no results after Update", "alpha", results)
+	}
+
+	if results, err := s.Search("beta"); err != nil {
+		t.Fatalf("Search() error: %v", err)
+	} else if len(results) != 1 {
+		t.Errorf("Search(%q) = %+v, want 1 result", "beta", results)
+	}
+}
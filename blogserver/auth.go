@@ -0,0 +1,50 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+
+	"github.com/shangyanjin/goblog/auth"
+)
+
+// loginTemplate is rendered for "/login".
+const loginTemplate = "login.html"
+
+// authenticator guards /submit/, /edit/ and /delete/ behind a login
+// session, configured from data/auth.json at startup.
+var authenticator *auth.Authenticator
+
+// loginPage is served on "/login": GET shows the form, POST verifies
+// credentials and starts a session.
+func loginPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method == "POST" {
+		if err := authenticator.Login(w, r, r.FormValue("username"), r.FormValue("password")); err != nil {
+			http.Redirect(w, r, "/login", http.StatusFound)
+			return
+		}
+
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	funcs := template.FuncMap{
+		"csrfToken": func() string { return auth.CSRFToken(w, r) },
+	}
+
+	if err := ts.ExecuteWithFuncs(w, loginTemplate, nil, funcs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// logoutPage is served on "/logout" and only accepts POST, so ending a
+// session is a state change guarded by RequireCSRF like submit/edit/delete,
+// not a GET a link or an <img> tag could trigger.
+func logoutPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	authenticator.Logout(w, r)
+	http.Redirect(w, r, "/", http.StatusFound)
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/shangyanjin/goblog/auth"
+)
+
+// authConfigFile is the on-disk representation of auth.Config. The
+// password is stored pre-hashed, so the server never needs (or writes)
+// the plaintext.
+type authConfigFile struct {
+	Username       string
+	PasswordHash   string
+	SessionTTLMins int
+}
+
+// defaultSessionTTL is used when SessionTTLMins is unset or non-positive.
+const defaultSessionTTL = 24 * time.Hour
+
+// loadAuthConfig reads the admin credentials from path. Unlike the feed
+// config, there is no sane default, so a missing or malformed file is a
+// startup error.
+func loadAuthConfig(path string) (auth.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return auth.Config{}, fmt.Errorf("auth: %s must exist and contain a username and bcrypt password hash: %w", path, err)
+	}
+	defer f.Close()
+
+	var cfg authConfigFile
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return auth.Config{}, err
+	}
+
+	ttl := time.Duration(cfg.SessionTTLMins) * time.Minute
+	if ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+
+	return auth.Config{
+		Username:     cfg.Username,
+		PasswordHash: cfg.PasswordHash,
+		SessionTTL:   ttl,
+	}, nil
+}
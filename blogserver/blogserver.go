@@ -1,15 +1,18 @@
 package main
 
 import (
-	"github.com/FliPPeh/goblog"
+	"github.com/shangyanjin/goblog/auth"
+	"github.com/shangyanjin/goblog/blog"
 	"bytes"
+	"flag"
 	"fmt"
 	"github.com/knieriem/markdown"
 	"html/template"
 	"net/http"
 	"os"
 	"os/signal"
-	"sort"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -19,15 +22,24 @@ const (
 	submitTemplate = "submit.html"
 )
 
-// templateFunc is a wrapped Handler function associated with a loaded template
-type templateFunc func(http.ResponseWriter, *http.Request, *template.Template)
+// Template directory, hot-reloaded when -dev is passed
+const templateDir = "templates"
 
-// Cached templates to save disk I/O
-var templateCache map[string]*template.Template
+// ts is the loaded set of templates, parsed once at startup and
+// optionally kept in sync with the filesystem by a -dev watcher.
+var ts *TemplateSet
 
 // Current state
 var blogState *blog.Blog
 
+// Feed metadata (base URL, author, title) used to render /feed.atom and
+// /feed.rss
+var feedConfig FeedConfig
+
+// pageSize is the number of entries shown per page on "/", set from the
+// -page-size flag.
+var pageSize = 10
+
 // Functions exported into templates
 var funcMap template.FuncMap = template.FuncMap{
 	"formatTime": tmplFormatTime,
@@ -50,43 +62,104 @@ func tmplMarkdown(t string) template.HTML {
 	return template.HTML(outbuf.String())
 }
 
-// makeTemplateHandler loads from disk or from cache the template passed by
-// the filename tmpl and creates a new functions that executes fn with the
-// loaded and validated template.
-func makeTemplateHandler(fn templateFunc, tmpl string) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		var tmp *template.Template
-
-		if val, ok := templateCache[tmpl]; ok {
-			tmp = val
-		} else {
-			var err error
-
-			tmp, err = template.New(tmpl).Funcs(funcMap).ParseFiles(tmpl)
-			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
-				return
-			}
-
-			templateCache[tmpl] = tmp
+// mainContent is the view model rendered by main.html.
+type mainContent struct {
+	Entries    []*blog.BlogEntry
+	Tags       []string
+	Page       int
+	PrevPage   int
+	NextPage   int
+	TotalPages int
+}
+
+// publishedEntries returns entries with Draft entries removed, preserving
+// order.
+func publishedEntries(entries []*blog.BlogEntry) []*blog.BlogEntry {
+	out := make([]*blog.BlogEntry, 0, len(entries))
+	for _, e := range entries {
+		if !e.Draft {
+			out = append(out, e)
 		}
+	}
+
+	return out
+}
 
-		fn(w, r, tmp)
+// parseTags splits a comma-separated tags form value into a clean slice,
+// dropping empty entries.
+func parseTags(raw string) []string {
+	var tags []string
+	for _, t := range strings.Split(raw, ",") {
+		if t = strings.TrimSpace(t); t != "" {
+			tags = append(tags, t)
+		}
 	}
+
+	return tags
 }
 
-// mainPage is the main page served on "/"
-func mainPage(w http.ResponseWriter, r *http.Request, t *template.Template) {
-	if r.URL.String() == "/" {
-		t.Execute(w, blogState)
-	} else {
+// mainPage is the main page served on "/", paginated via "?page=N".
+func mainPage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
 		http.NotFound(w, r)
 		return
 	}
+
+	entries := publishedEntries(blogState.Entries())
+
+	renderEntryList(w, entries, pageFromRequest(r))
+}
+
+// pageFromRequest parses the "page" query parameter, defaulting to 1.
+func pageFromRequest(r *http.Request) int {
+	page, err := strconv.Atoi(r.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		return 1
+	}
+
+	return page
+}
+
+// renderEntryList slices entries to the requested page and renders
+// mainTemplate with the resulting mainContent.
+func renderEntryList(w http.ResponseWriter, entries []*blog.BlogEntry, page int) {
+	totalPages := (len(entries) + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	if page > totalPages {
+		page = totalPages
+	}
+
+	start := (page - 1) * pageSize
+	if start > len(entries) {
+		start = len(entries)
+	}
+	end := start + pageSize
+	if end > len(entries) {
+		end = len(entries)
+	}
+
+	data := mainContent{
+		Entries:    entries[start:end],
+		Tags:       blogState.TagNames(),
+		Page:       page,
+		TotalPages: totalPages,
+	}
+	if page > 1 {
+		data.PrevPage = page - 1
+	}
+	if page < totalPages {
+		data.NextPage = page + 1
+	}
+
+	if err := ts.Execute(w, mainTemplate, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
 }
 
 // submitPage is the submission page served on "/submit/"
-func submitPage(w http.ResponseWriter, r *http.Request, t *template.Template) {
+func submitPage(w http.ResponseWriter, r *http.Request) {
 	if r.Method == "POST" {
 		if r.FormValue("title") == "" || r.FormValue("content") == "" {
 			http.Redirect(w, r, "/submit/", http.StatusFound)
@@ -97,51 +170,112 @@ func submitPage(w http.ResponseWriter, r *http.Request, t *template.Template) {
 			Title:   r.FormValue("title"),
 			Content: r.FormValue("content"),
 			Date:    time.Now(),
+			Draft:   r.FormValue("draft") != "",
+			Tags:    parseTags(r.FormValue("tags")),
 		}
 
-		blogState.AddEntry(newEntry)
-		sort.Sort(blog.ByDate{blogState.Entries})
+		if err := blogState.AddEntry(newEntry); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
 
 		http.Redirect(w, r, "/", http.StatusFound)
 	} else {
-		err := t.Execute(w, nil)
-		if err != nil {
+		funcs := template.FuncMap{
+			"csrfToken": func() string { return auth.CSRFToken(w, r) },
+		}
+
+		if err := ts.ExecuteWithFuncs(w, submitTemplate, nil, funcs); err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
 		}
 	}
 }
 
-// deferCleanup listens for SIGIT (Ctrl-C) and saves the state on disk before
-// exiting.
+// deferCleanup listens for SIGINT (Ctrl-C) and closes the storage backend
+// before exiting, so it can flush or release its resources cleanly.
 func deferCleanup() {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt)
 
 	go func() {
 		for sig := range c {
-			fmt.Printf("Ctrl-C (%s) caught, saving state...\n", sig)
-			blogState.Save("data/entries.json")
+			fmt.Printf("Ctrl-C (%s) caught, closing storage...\n", sig)
+			blogState.Close()
 			os.Exit(0)
 		}
 	}()
 }
 
 func main() {
+	dev := flag.Bool("dev", false, "watch the templates directory and hot-reload on change")
+	storageDriver := flag.String("storage", "json", "storage backend: json, bolt or sqlite")
+	importDir := flag.String("import", "", "import Markdown files with frontmatter from this directory at startup")
+	flag.IntVar(&pageSize, "page-size", pageSize, "number of entries per page on the main listing")
+	flag.Parse()
+
+	if pageSize < 1 {
+		pageSize = 1
+	}
+
 	var err error
-	templateCache = make(map[string]*template.Template)
 
-	blogState, err = blog.NewFromFile("data/entries.json")
+	ts, err = NewTemplateSet(templateDir)
+	if err != nil {
+		panic("Templates could not be loaded: " + err.Error())
+	}
+
+	if *dev {
+		if err := ts.Watch(nil); err != nil {
+			panic("Template watcher could not start: " + err.Error())
+		}
+	}
+
+	storage, err := newStorage(*storageDriver)
+	if err != nil {
+		panic("Storage backend could not be opened: " + err.Error())
+	}
+
+	if *importDir != "" {
+		n, err := blog.Import(storage, *importDir)
+		if err != nil {
+			panic("Import failed: " + err.Error())
+		}
+		fmt.Printf("Imported %d entries from %s\n", n, *importDir)
+	}
+
+	blogState, err = blog.New(storage)
 	if err != nil {
 		panic("Blog entries could not be loaded")
 	}
 
-	sort.Sort(blog.ByDate{blogState.Entries})
+	feedConfig, err = loadFeedConfig("data/feed.json")
+	if err != nil {
+		panic("Feed config could not be loaded")
+	}
+
+	authConfig, err := loadAuthConfig("data/auth.json")
+	if err != nil {
+		panic(err.Error())
+	}
+	authenticator = auth.New(authConfig)
 
 	deferCleanup()
 
-	http.HandleFunc("/", makeTemplateHandler(mainPage, mainTemplate))
-	http.HandleFunc("/submit/", makeTemplateHandler(submitPage, submitTemplate))
+	protect := func(fn http.HandlerFunc) http.Handler {
+		return authenticator.RequireAuth(auth.RequireCSRF(fn))
+	}
+
+	http.HandleFunc("/", mainPage)
+	http.HandleFunc("GET /post/{id}", postPage)
+	http.HandleFunc("GET /tag/{name}", tagPage)
+	http.Handle("/submit/", protect(submitPage))
+	http.Handle("/edit/", protect(editPage))
+	http.Handle("/delete/", protect(deletePage))
+	http.Handle("/login", auth.RequireCSRF(http.HandlerFunc(loginPage)))
+	http.Handle("/logout", protect(logoutPage))
+	http.Handle("/admin/import", protect(importPage))
+	http.HandleFunc("/feed.atom", feedAtomHandler)
+	http.HandleFunc("/feed.rss", feedRSSHandler)
 	http.Handle("/static/",
 		http.StripPrefix("/static", http.FileServer(http.Dir("./static/"))),
 	)
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"html/template"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/shangyanjin/goblog/auth"
+	"github.com/shangyanjin/goblog/blog"
+)
+
+// editTemplate is the template used to show and submit edits to an
+// existing entry.
+const editTemplate = "edit.html"
+
+// editPage is served on "/edit/{id}": GET shows the entry for editing,
+// POST applies the changes.
+func editPage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/edit/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	entry, err := blogState.Storage.Get(id)
+	if err == blog.ErrNotFound {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if r.Method == "POST" {
+		if r.FormValue("title") == "" || r.FormValue("content") == "" {
+			http.Redirect(w, r, r.URL.Path, http.StatusFound)
+			return
+		}
+
+		entry.Title = r.FormValue("title")
+		entry.Content = r.FormValue("content")
+		entry.Draft = r.FormValue("draft") != ""
+		entry.Tags = parseTags(r.FormValue("tags"))
+
+		if err := blogState.Storage.Update(entry); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := blogState.Refresh(); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		http.Redirect(w, r, "/", http.StatusFound)
+		return
+	}
+
+	funcs := template.FuncMap{
+		"csrfToken": func() string { return auth.CSRFToken(w, r) },
+	}
+
+	if err := ts.ExecuteWithFuncs(w, editTemplate, entry, funcs); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// deletePage is served on "/delete/{id}" and only accepts POST.
+func deletePage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/delete/"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := blogState.Storage.Delete(id); err != nil && err != blog.ErrNotFound {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := blogState.Refresh(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	http.Redirect(w, r, "/", http.StatusFound)
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/shangyanjin/goblog/blog"
+)
+
+// atomFeed is the root element of an RFC 4287 Atom feed.
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Published string      `xml:"published"`
+	Updated   string      `xml:"updated"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",cdata"`
+}
+
+// entryTagURI builds a stable tag URI (RFC 4151) for an entry, of the form
+// tag:<host>,<yyyy-mm-dd>:/<entryID>.
+func entryTagURI(host string, entry *blog.BlogEntry) string {
+	return "tag:" + host + "," + entry.Date.Format("2006-01-02") + ":/" + strconv.Itoa(entry.ID)
+}
+
+// buildAtomFeed renders b's entries into an Atom feed described by cfg.
+func buildAtomFeed(cfg FeedConfig, b *blog.Blog) (atomFeed, error) {
+	host := cfg.BaseURL
+	if u, err := url.Parse(cfg.BaseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	updated := time.Time{}
+	blogEntries := publishedEntries(b.Entries())
+	entries := make([]atomEntry, 0, len(blogEntries))
+
+	for _, e := range blogEntries {
+		if e.Date.After(updated) {
+			updated = e.Date
+		}
+
+		entries = append(entries, atomEntry{
+			ID:        entryTagURI(host, e),
+			Title:     e.Title,
+			Published: e.Date.Format(time.RFC3339),
+			Updated:   e.Date.Format(time.RFC3339),
+			Content: atomContent{
+				Type: "html",
+				Body: string(tmplMarkdown(e.Content)),
+			},
+		})
+	}
+
+	if updated.IsZero() {
+		updated = time.Now()
+	}
+
+	return atomFeed{
+		ID:      cfg.BaseURL + "/feed.atom",
+		Title:   cfg.Title,
+		Updated: updated.Format(time.RFC3339),
+		Author:  atomAuthor{Name: cfg.Author},
+		Link:    atomLink{Rel: "self", Href: cfg.BaseURL + "/feed.atom"},
+		Entries: entries,
+	}, nil
+}
+
+// feedAtomHandler serves /feed.atom.
+func feedAtomHandler(w http.ResponseWriter, r *http.Request) {
+	feed, err := buildAtomFeed(feedConfig, blogState)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// rssFeed is the root element of an RSS 2.0 feed, built from the same
+// entries as the Atom feed for a given request.
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title          string    `xml:"title"`
+	Link           string    `xml:"link"`
+	Description    string    `xml:"description"`
+	ManagingEditor string    `xml:"managingEditor,omitempty"`
+	Items          []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+// rssGUID is a tag-URI guid, not a dereferenceable link, so isPermaLink must
+// be set to "false" or RSS 2.0 readers default to treating it as one.
+type rssGUID struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
+}
+
+// buildRSSFeed renders b's entries into an RSS 2.0 feed described by cfg.
+func buildRSSFeed(cfg FeedConfig, b *blog.Blog) rssFeed {
+	host := cfg.BaseURL
+	if u, err := url.Parse(cfg.BaseURL); err == nil && u.Host != "" {
+		host = u.Host
+	}
+
+	blogEntries := publishedEntries(b.Entries())
+	items := make([]rssItem, 0, len(blogEntries))
+	for _, e := range blogEntries {
+		items = append(items, rssItem{
+			Title:       e.Title,
+			Link:        cfg.BaseURL + "/post/" + strconv.Itoa(e.ID),
+			GUID:        rssGUID{Value: entryTagURI(host, e), IsPermaLink: "false"},
+			PubDate:     e.Date.Format(time.RFC1123Z),
+			Description: string(tmplMarkdown(e.Content)),
+		})
+	}
+
+	return rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:          cfg.Title,
+			Link:           cfg.BaseURL,
+			Description:    cfg.Title,
+			ManagingEditor: cfg.Author,
+			Items:          items,
+		},
+	}
+}
+
+// feedRSSHandler serves /feed.rss.
+func feedRSSHandler(w http.ResponseWriter, r *http.Request) {
+	feed := buildRSSFeed(feedConfig, blogState)
+
+	w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+	w.Write([]byte(xml.Header))
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
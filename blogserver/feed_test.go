@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/xml"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shangyanjin/goblog/blog"
+)
+
+func testBlog(t *testing.T) *blog.Blog {
+	t.Helper()
+
+	first, _ := time.Parse(time.RFC3339, "2026-01-02T15:04:05Z")
+	second, _ := time.Parse(time.RFC3339, "2026-01-03T09:00:00Z")
+
+	storage, err := blog.NewJSONStorage(filepath.Join(t.TempDir(), "entries.json"))
+	if err != nil {
+		t.Fatalf("NewJSONStorage() error: %v", err)
+	}
+
+	b, err := blog.New(storage)
+	if err != nil {
+		t.Fatalf("blog.New() error: %v", err)
+	}
+
+	if err := b.AddEntry(&blog.BlogEntry{Title: "First post", Content: "hello", Date: first}); err != nil {
+		t.Fatalf("AddEntry() error: %v", err)
+	}
+	if err := b.AddEntry(&blog.BlogEntry{Title: "Second post", Content: "**bold** text", Date: second}); err != nil {
+		t.Fatalf("AddEntry() error: %v", err)
+	}
+
+	return b
+}
+
+func testFeedConfig() FeedConfig {
+	return FeedConfig{
+		BaseURL: "https://example.com",
+		Author:  "Jane Doe",
+		Title:   "Jane's Blog",
+	}
+}
+
+func TestEntryTagURI(t *testing.T) {
+	entries := testBlog(t).Entries()
+
+	got := entryTagURI("example.com", entries[1])
+	want := "tag:example.com,2026-01-02:/1"
+
+	if got != want {
+		t.Fatalf("entryTagURI() = %q, want %q", got, want)
+	}
+}
+
+func TestBuildAtomFeed(t *testing.T) {
+	feed, err := buildAtomFeed(testFeedConfig(), testBlog(t))
+	if err != nil {
+		t.Fatalf("buildAtomFeed() error: %v", err)
+	}
+
+	if feed.ID != "https://example.com/feed.atom" {
+		t.Errorf("feed ID = %q, want %q", feed.ID, "https://example.com/feed.atom")
+	}
+	if feed.Updated != "2026-01-03T09:00:00Z" {
+		t.Errorf("feed Updated = %q, want max entry date", feed.Updated)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("len(feed.Entries) = %d, want 2", len(feed.Entries))
+	}
+
+	first := feed.Entries[1]
+	if first.ID != "tag:example.com,2026-01-02:/1" {
+		t.Errorf("entry ID = %q, want tag URI", first.ID)
+	}
+	if first.Content.Type != "html" {
+		t.Errorf("entry Content.Type = %q, want %q", first.Content.Type, "html")
+	}
+
+	out, err := xml.Marshal(feed)
+	if err != nil {
+		t.Fatalf("xml.Marshal() error: %v", err)
+	}
+
+	s := string(out)
+	if !containsAll(s, []string{
+		`<feed xmlns="http://www.w3.org/2005/Atom">`,
+		`<link rel="self" href="https://example.com/feed.atom">`,
+		`<author><name>Jane Doe</name></author>`,
+		`tag:example.com,2026-01-02:/1`,
+	}) {
+		t.Errorf("unexpected atom XML: %s", s)
+	}
+}
+
+func TestBuildRSSFeed(t *testing.T) {
+	feed := buildRSSFeed(testFeedConfig(), testBlog(t))
+
+	if feed.Version != "2.0" {
+		t.Errorf("feed Version = %q, want %q", feed.Version, "2.0")
+	}
+	if len(feed.Channel.Items) != 2 {
+		t.Fatalf("len(feed.Channel.Items) = %d, want 2", len(feed.Channel.Items))
+	}
+	if feed.Channel.Items[1].Link != "https://example.com/post/1" {
+		t.Errorf("item Link = %q, want %q", feed.Channel.Items[1].Link, "https://example.com/post/1")
+	}
+
+	out, err := xml.Marshal(feed)
+	if err != nil {
+		t.Fatalf("xml.Marshal() error: %v", err)
+	}
+
+	if !containsAll(string(out), []string{
+		`<rss version="2.0">`,
+		`<managingEditor>Jane Doe</managingEditor>`,
+		`<guid isPermaLink="false">tag:example.com,2026-01-02:/1</guid>`,
+	}) {
+		t.Errorf("unexpected rss XML: %s", out)
+	}
+}
+
+func TestFeedsExcludeDrafts(t *testing.T) {
+	b := testBlog(t)
+	if err := b.AddEntry(&blog.BlogEntry{Title: "Draft post", Content: "shh", Date: time.Now(), Draft: true}); err != nil {
+		t.Fatalf("AddEntry() error: %v", err)
+	}
+
+	atomFeed, err := buildAtomFeed(testFeedConfig(), b)
+	if err != nil {
+		t.Fatalf("buildAtomFeed() error: %v", err)
+	}
+	if len(atomFeed.Entries) != 2 {
+		t.Errorf("len(atomFeed.Entries) = %d, want 2 (draft excluded)", len(atomFeed.Entries))
+	}
+
+	rssFeed := buildRSSFeed(testFeedConfig(), b)
+	if len(rssFeed.Channel.Items) != 2 {
+		t.Errorf("len(rssFeed.Channel.Items) = %d, want 2 (draft excluded)", len(rssFeed.Channel.Items))
+	}
+}
+
+func containsAll(haystack string, needles []string) bool {
+	for _, n := range needles {
+		if !strings.Contains(haystack, n) {
+			return false
+		}
+	}
+	return true
+}
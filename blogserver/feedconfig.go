@@ -0,0 +1,43 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+)
+
+// FeedConfig holds the metadata needed to render the Atom/RSS feeds.
+type FeedConfig struct {
+	// BaseURL is the public URL the blog is served from, e.g.
+	// "https://example.com". It is used both as the feed's self link and,
+	// via its host, to build tag URIs for entry IDs.
+	BaseURL string
+	Author  string
+	Title   string
+}
+
+// defaultFeedConfig is used when no config file is present, so the feeds
+// still render (with obviously placeholder values) on a fresh checkout.
+var defaultFeedConfig = FeedConfig{
+	BaseURL: "http://localhost:8080",
+	Author:  "Anonymous",
+	Title:   "goblog",
+}
+
+// loadFeedConfig reads the feed configuration from path. If the file does
+// not exist, defaultFeedConfig is returned.
+func loadFeedConfig(path string) (FeedConfig, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return defaultFeedConfig, nil
+	} else if err != nil {
+		return FeedConfig{}, err
+	}
+	defer f.Close()
+
+	cfg := defaultFeedConfig
+	if err := json.NewDecoder(f).Decode(&cfg); err != nil {
+		return FeedConfig{}, err
+	}
+
+	return cfg, nil
+}
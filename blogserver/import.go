@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/shangyanjin/goblog/blog"
+)
+
+// importPage is served on "/admin/import" and runs the same Markdown
+// frontmatter import as the -import startup flag, against the "dir" form
+// value of the request.
+func importPage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	dir := r.FormValue("dir")
+	if dir == "" {
+		http.Error(w, "dir is required", http.StatusBadRequest)
+		return
+	}
+
+	n, err := blog.Import(blogState.Storage, dir)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if err := blogState.Refresh(); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	fmt.Fprintf(w, "imported %d entries\n", n)
+}
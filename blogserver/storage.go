@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shangyanjin/goblog/blog"
+)
+
+// newStorage opens the blog.Storage backend named by driver, one of
+// "json", "bolt" or "sqlite".
+func newStorage(driver string) (blog.Storage, error) {
+	switch driver {
+	case "json":
+		return blog.NewJSONStorage("data/entries.json")
+	case "bolt":
+		return blog.NewBoltStorage("data/blog.bolt")
+	case "sqlite":
+		return blog.NewSQLiteStorage("data/blog.sqlite")
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", driver)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/shangyanjin/goblog/blog"
+)
+
+// postTemplate renders a single entry on "/post/{id}".
+const postTemplate = "post.html"
+
+// postPage is served on "/post/{id}" and renders a single published
+// entry. Draft entries 404, matching how they're hidden from "/".
+func postPage(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	entry, err := blogState.Storage.Get(id)
+	if err == blog.ErrNotFound {
+		http.NotFound(w, r)
+		return
+	} else if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if entry.Draft {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := ts.Execute(w, postTemplate, entry); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// tagPage is served on "/tag/{name}" and lists published entries tagged
+// with name, through the same mainTemplate and pagination as "/".
+func tagPage(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if name == "" {
+		http.NotFound(w, r)
+		return
+	}
+
+	entries := publishedEntries(blogState.Tagged(name))
+
+	renderEntryList(w, entries, pageFromRequest(r))
+}
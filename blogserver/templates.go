@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"html/template"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sync"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// TemplateSet holds the full set of parsed templates, safe for concurrent
+// use by handlers while a watcher goroutine reparses them in the
+// background.
+type TemplateSet struct {
+	mu  sync.RWMutex
+	tpl *template.Template
+	dir string
+}
+
+// NewTemplateSet parses every "*.html" file under dir into a single
+// template.Template, keyed by filename, with funcMap available to all of
+// them.
+func NewTemplateSet(dir string) (*TemplateSet, error) {
+	ts := &TemplateSet{dir: dir}
+	if err := ts.reload(); err != nil {
+		return nil, err
+	}
+
+	return ts, nil
+}
+
+// requestFuncs are placeholders for the request-scoped helpers that
+// handlers bind through ExecuteWithFuncs. Templates are parsed against
+// them so that a call such as {{csrfToken}} is known at parse time.
+var requestFuncs = template.FuncMap{
+	"csrfToken": func() string { return "" },
+}
+
+// reload re-parses the templates directory and swaps it in atomically.
+func (ts *TemplateSet) reload() error {
+	tpl, err := template.New(filepath.Base(ts.dir)).Funcs(funcMap).Funcs(requestFuncs).ParseGlob(filepath.Join(ts.dir, "*.html"))
+	if err != nil {
+		return err
+	}
+
+	ts.mu.Lock()
+	ts.tpl = tpl
+	ts.mu.Unlock()
+
+	return nil
+}
+
+// Execute renders the named template into a buffer first and only writes
+// to w once that succeeds, so a parse or execution error never produces a
+// half-written 200 response.
+func (ts *TemplateSet) Execute(w http.ResponseWriter, name string, data interface{}) error {
+	return ts.execute(w, name, data, nil)
+}
+
+// ExecuteWithFuncs behaves like Execute but additionally makes funcs
+// available to the template, for request-scoped helpers (e.g. a
+// csrfToken function bound to the current ResponseWriter/Request) that
+// can't live in the shared, process-wide funcMap.
+func (ts *TemplateSet) ExecuteWithFuncs(w http.ResponseWriter, name string, data interface{}, funcs template.FuncMap) error {
+	return ts.execute(w, name, data, funcs)
+}
+
+func (ts *TemplateSet) execute(w http.ResponseWriter, name string, data interface{}, funcs template.FuncMap) error {
+	ts.mu.RLock()
+	tpl := ts.tpl
+	ts.mu.RUnlock()
+
+	// Execute a clone, never the shared set itself: html/template refuses
+	// to Clone a set once any of its templates has been executed, which
+	// would break every later ExecuteWithFuncs call.
+	tpl, err := tpl.Clone()
+	if err != nil {
+		return err
+	}
+	if funcs != nil {
+		tpl = tpl.Funcs(funcs)
+	}
+
+	var buf bytes.Buffer
+	if err := tpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return err
+	}
+
+	_, err = buf.WriteTo(w)
+	return err
+}
+
+// Watch starts a goroutine that reparses the template set whenever a file
+// under its directory is written, created or removed, until stop is
+// closed. Reload errors are logged but leave the last good templates in
+// place.
+func (ts *TemplateSet) Watch(stop <-chan struct{}) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+
+	if err := watcher.Add(ts.dir); err != nil {
+		watcher.Close()
+		return err
+	}
+
+	go func() {
+		defer watcher.Close()
+
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Remove) != 0 {
+					if err := ts.reload(); err != nil {
+						log.Printf("templates: reload of %s failed: %v", ts.dir, err)
+					}
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("templates: watcher error: %v", err)
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTemplateSetExecuteWritesNothingOnError(t *testing.T) {
+	dir := t.TempDir()
+	broken := "start{{.Missing.Field}}"
+	if err := os.WriteFile(filepath.Join(dir, "broken.html"), []byte(broken), 0644); err != nil {
+		t.Fatalf("WriteFile() error: %v", err)
+	}
+
+	ts, err := NewTemplateSet(dir)
+	if err != nil {
+		t.Fatalf("NewTemplateSet() error: %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	if err := ts.Execute(w, "broken.html", struct{}{}); err == nil {
+		t.Fatal("Execute() error = nil, want error from the failing field access")
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("Execute() wrote %q to w after failing, want nothing", w.Body.String())
+	}
+}